@@ -0,0 +1,208 @@
+package whatsup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFailureThreshold = 3
+	defaultSuccessThreshold = 2
+)
+
+// AlertState tracks an endpoint's recent check history for the purposes of alert
+// de-duplication, flapping suppression, and recovery notifications.
+type AlertState struct {
+	ConsecutiveFailures  int       `json:"consecutiveFailures"`
+	ConsecutiveSuccesses int       `json:"consecutiveSuccesses"`
+	Alerting             bool      `json:"alerting"`
+	LastNotified         time.Time `json:"lastNotified"`
+}
+
+// AlertStore persists AlertState per endpoint to a JSON file, so that restarting the one-shot
+// whatsup CLI doesn't immediately re-notify about an outage it has already alerted on.
+type AlertStore struct {
+	mu     sync.Mutex
+	path   string
+	States map[string]AlertState
+}
+
+// LoadAlertStore reads the alert state file at path, if any, and returns an AlertStore backed by
+// it. An empty path returns an in-memory-only store whose Save is a no-op.
+func LoadAlertStore(path string) (*AlertStore, error) {
+	store := &AlertStore{path: path, States: make(map[string]AlertState)}
+
+	if path == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &store.States); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Save writes the store's current state back to its path. It is a no-op if the store was loaded
+// without a path.
+func (s *AlertStore) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.States, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+// Get returns the stored AlertState for the named endpoint, or its zero value if none exists yet.
+func (s *AlertStore) Get(name string) AlertState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.States[name]
+}
+
+// Set stores the AlertState for the named endpoint.
+func (s *AlertStore) Set(name string, state AlertState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.States[name] = state
+}
+
+// AlertEvent is a notification-worthy change in an endpoint's status: it going down, it
+// recovering, or a reminder that it is still down.
+type AlertEvent struct {
+	Result  CheckResult
+	Kind    string // "down", "resolved", or "reminder"
+	Recover *RecoverResult
+}
+
+// alertEvaluation pairs an AlertEvent worth notifying about with the AlertState transition that
+// should be committed to the store once that notification has actually been delivered.
+type alertEvaluation struct {
+	event   AlertEvent
+	pending AlertState
+}
+
+// evaluateAlert updates the endpoint's consecutive failure/success counters in store unconditionally,
+// so thresholds are computed correctly on the next check no matter what happens next. It returns the
+// AlertEvent worth notifying about, if any, and the AlertState reflecting that notification having
+// been delivered: the caller must commit that state via store.Set itself, and only once notifyAll
+// has actually succeeded, so that a failed delivery doesn't suppress a retry on the next check.
+// Notifications are de-duplicated: a "down" event only fires once the endpoint has failed
+// FailureThreshold times in a row, a "resolved" event only fires once it has then succeeded
+// SuccessThreshold times in a row, and while an outage is ongoing further notifications are
+// suppressed unless ReminderInterval has elapsed since the last one.
+func evaluateAlert(ep Endpoint, result CheckResult, store *AlertStore, now time.Time) (AlertEvent, AlertState, bool) {
+	failureThreshold := ep.FailureThreshold
+	if failureThreshold <= 0 {
+		failureThreshold = defaultFailureThreshold
+	}
+	successThreshold := ep.SuccessThreshold
+	if successThreshold <= 0 {
+		successThreshold = defaultSuccessThreshold
+	}
+
+	state := store.Get(result.Endpoint)
+
+	if result.Up {
+		state.ConsecutiveFailures = 0
+		state.ConsecutiveSuccesses++
+		store.Set(result.Endpoint, state)
+
+		if state.Alerting && state.ConsecutiveSuccesses >= successThreshold {
+			pending := state
+			pending.Alerting = false
+			pending.ConsecutiveSuccesses = 0
+			pending.LastNotified = now
+			return AlertEvent{Result: result, Kind: "resolved"}, pending, true
+		}
+
+		return AlertEvent{}, state, false
+	}
+
+	state.ConsecutiveSuccesses = 0
+	state.ConsecutiveFailures++
+	store.Set(result.Endpoint, state)
+
+	if !state.Alerting {
+		if state.ConsecutiveFailures >= failureThreshold {
+			pending := state
+			pending.Alerting = true
+			pending.LastNotified = now
+			return AlertEvent{Result: result, Kind: "down"}, pending, true
+		}
+
+		return AlertEvent{}, state, false
+	}
+
+	reminderInterval := time.Duration(ep.ReminderInterval)
+	if reminderInterval > 0 && now.Sub(state.LastNotified) >= reminderInterval {
+		pending := state
+		pending.LastNotified = now
+		return AlertEvent{Result: result, Kind: "reminder"}, pending, true
+	}
+
+	return AlertEvent{}, state, false
+}
+
+// evaluateAlerts runs evaluateAlert for every result against its matching endpoint config and
+// returns the evaluations worth notifying about, each paired with the AlertState its delivery
+// should commit.
+func evaluateAlerts(endpoints []Endpoint, results []CheckResult, store *AlertStore, now time.Time) []alertEvaluation {
+	endpointsByName := indexEndpointsByName(endpoints)
+
+	var evaluations []alertEvaluation
+	for _, result := range results {
+		if event, pending, fire := evaluateAlert(endpointsByName[result.Endpoint], result, store, now); fire {
+			evaluations = append(evaluations, alertEvaluation{event: event, pending: pending})
+		}
+	}
+
+	return evaluations
+}
+
+// summarizeAlertEvents renders the alert events worth notifying about from a single run into a
+// CheckSummary.
+func summarizeAlertEvents(events []AlertEvent) CheckSummary {
+	allUp := true
+	var msg strings.Builder
+
+	for _, e := range events {
+		switch e.Kind {
+		case "down":
+			allUp = false
+			msg.WriteString(fmt.Sprintf("🔥 %s is down! Error: %s \n\n", e.Result.Endpoint, e.Result.Err))
+			if e.Recover != nil {
+				msg.WriteString(formatRecoverResult(*e.Recover))
+			}
+		case "reminder":
+			allUp = false
+			msg.WriteString(fmt.Sprintf("⏰ %s is still down. Error: %s \n\n", e.Result.Endpoint, e.Result.Err))
+		case "resolved":
+			msg.WriteString(fmt.Sprintf("👍 %s has recovered. \n\n", e.Result.Endpoint))
+		}
+	}
+
+	return CheckSummary{AllUp: allUp, Msg: strings.TrimSpace(msg.String())}
+}
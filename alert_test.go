@@ -0,0 +1,87 @@
+package whatsup
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluateAlertFiresDownOnlyAfterFailureThreshold(t *testing.T) {
+	ep := Endpoint{Name: "api", FailureThreshold: 3}
+	store, err := LoadAlertStore("")
+	if err != nil {
+		t.Fatalf("LoadAlertStore: %v", err)
+	}
+
+	down := CheckResult{Endpoint: "api", Up: false}
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		if _, _, fire := evaluateAlert(ep, down, store, now); fire {
+			t.Fatalf("attempt %d: fired before FailureThreshold was reached", i+1)
+		}
+	}
+
+	event, pending, fire := evaluateAlert(ep, down, store, now)
+	if !fire || event.Kind != "down" {
+		t.Fatalf("expected a down event on reaching FailureThreshold, got fire=%v event=%+v", fire, event)
+	}
+	if !pending.Alerting {
+		t.Fatalf("expected pending state to mark Alerting, got %+v", pending)
+	}
+}
+
+func TestEvaluateAlertDoesNotSuppressRetryAfterFailedNotify(t *testing.T) {
+	ep := Endpoint{Name: "api", FailureThreshold: 1}
+	store, err := LoadAlertStore("")
+	if err != nil {
+		t.Fatalf("LoadAlertStore: %v", err)
+	}
+
+	down := CheckResult{Endpoint: "api", Up: false}
+	now := time.Now()
+
+	event, _, fire := evaluateAlert(ep, down, store, now)
+	if !fire || event.Kind != "down" {
+		t.Fatalf("expected an initial down event, got fire=%v event=%+v", fire, event)
+	}
+
+	// Simulate a failed notifyAll: the caller never commits the returned pending state, so
+	// store.Get must still report Alerting == false and the next check must fire again.
+	if state := store.Get("api"); state.Alerting {
+		t.Fatalf("pending state was committed without a successful notify: %+v", state)
+	}
+
+	event, _, fire = evaluateAlert(ep, down, store, now.Add(time.Second))
+	if !fire || event.Kind != "down" {
+		t.Fatalf("expected a retried down event after the failed notify, got fire=%v event=%+v", fire, event)
+	}
+}
+
+func TestEvaluateAlertResolvedRequiresSuccessThreshold(t *testing.T) {
+	ep := Endpoint{Name: "api", FailureThreshold: 1, SuccessThreshold: 2}
+	store, err := LoadAlertStore("")
+	if err != nil {
+		t.Fatalf("LoadAlertStore: %v", err)
+	}
+
+	now := time.Now()
+	_, pending, fire := evaluateAlert(ep, CheckResult{Endpoint: "api", Up: false}, store, now)
+	if !fire {
+		t.Fatalf("expected the initial failure to fire a down event")
+	}
+	store.Set("api", pending)
+
+	up := CheckResult{Endpoint: "api", Up: true}
+
+	if _, _, fire := evaluateAlert(ep, up, store, now); fire {
+		t.Fatalf("resolved event fired before SuccessThreshold was reached")
+	}
+
+	event, pending, fire := evaluateAlert(ep, up, store, now)
+	if !fire || event.Kind != "resolved" {
+		t.Fatalf("expected a resolved event on reaching SuccessThreshold, got fire=%v event=%+v", fire, event)
+	}
+	if pending.Alerting {
+		t.Fatalf("expected pending state to clear Alerting, got %+v", pending)
+	}
+}
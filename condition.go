@@ -0,0 +1,207 @@
+package whatsup
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// evaluateConditions evaluates every condition against the given result, returning true only if
+// all of them pass. An endpoint with no conditions is considered up as long as it was probed
+// without error.
+func evaluateConditions(conditions []string, result Result) (bool, error) {
+	for _, condition := range conditions {
+		ok, err := evaluateCondition(condition, result)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// evaluateCondition evaluates a single condition string, e.g. "[STATUS] == 200" or
+// "[BODY].status == \"ok\"" or `[BODY].message == "not found"`, against a probe Result.
+func evaluateCondition(condition string, result Result) (bool, error) {
+	lhs, op, rhs, err := splitCondition(condition)
+	if err != nil {
+		return false, err
+	}
+
+	placeholder, path, err := parsePlaceholder(lhs)
+	if err != nil {
+		return false, err
+	}
+
+	switch placeholder {
+	case "STATUS":
+		return compareNumbers(float64(result.StatusCode), op, rhs)
+	case "RESPONSE_TIME":
+		return compareDurations(result.ResponseTime, op, rhs)
+	case "CERTIFICATE_EXPIRATION":
+		return compareDurations(time.Until(result.CertExpiration), op, rhs)
+	case "IP":
+		return compareStrings(result.IP, op, rhs)
+	case "HEADER":
+		return compareHeader(result.Headers, path, op, rhs)
+	case "BODY":
+		return compareBody(result.Body, path, op, rhs)
+	default:
+		return false, fmt.Errorf("unknown condition placeholder: %s", placeholder)
+	}
+}
+
+// splitCondition splits a condition string into its left-hand side, operator, and right-hand side,
+// e.g. "[STATUS] == 200" or `[BODY].message == "not found"`. The right-hand side is everything
+// after the operator, so unlike the other two fields it may itself contain spaces.
+func splitCondition(condition string) (lhs string, op string, rhs string, err error) {
+	condition = strings.TrimSpace(condition)
+
+	fields := strings.Fields(condition)
+	if len(fields) < 3 {
+		return "", "", "", fmt.Errorf("malformed condition: %q", condition)
+	}
+	lhs, op = fields[0], fields[1]
+
+	rest := strings.TrimLeft(strings.TrimPrefix(condition, lhs), " \t")
+	rhs = strings.TrimSpace(strings.TrimPrefix(rest, op))
+	if rhs == "" {
+		return "", "", "", fmt.Errorf("malformed condition: %q", condition)
+	}
+
+	return lhs, op, rhs, nil
+}
+
+// parsePlaceholder splits a condition's left-hand side, e.g. "[BODY].status", into its placeholder
+// name ("BODY") and an optional dot-separated JSON path ("status").
+func parsePlaceholder(lhs string) (placeholder string, path string, err error) {
+	if !strings.HasPrefix(lhs, "[") {
+		return "", "", fmt.Errorf("malformed condition placeholder: %q", lhs)
+	}
+
+	end := strings.Index(lhs, "]")
+	if end == -1 {
+		return "", "", fmt.Errorf("malformed condition placeholder: %q", lhs)
+	}
+
+	placeholder = lhs[1:end]
+	path = strings.TrimPrefix(lhs[end+1:], ".")
+
+	return placeholder, path, nil
+}
+
+// compareNumbers compares a numeric left-hand side against a condition's right-hand side.
+func compareNumbers(lhs float64, op string, rhs string) (bool, error) {
+	rhsVal, err := strconv.ParseFloat(rhs, 64)
+	if err != nil {
+		return false, fmt.Errorf("expected a number, got %q", rhs)
+	}
+
+	switch op {
+	case "==":
+		return lhs == rhsVal, nil
+	case "!=":
+		return lhs != rhsVal, nil
+	case "<":
+		return lhs < rhsVal, nil
+	case "<=":
+		return lhs <= rhsVal, nil
+	case ">":
+		return lhs > rhsVal, nil
+	case ">=":
+		return lhs >= rhsVal, nil
+	default:
+		return false, fmt.Errorf("unsupported operator: %s", op)
+	}
+}
+
+// compareDurations compares a duration left-hand side against a condition's right-hand side, which
+// may be a Go duration string (e.g. "168h") or a bare number of milliseconds (e.g. "500").
+func compareDurations(lhs time.Duration, op string, rhs string) (bool, error) {
+	rhsVal, err := time.ParseDuration(rhs)
+	if err != nil {
+		ms, msErr := strconv.ParseFloat(rhs, 64)
+		if msErr != nil {
+			return false, fmt.Errorf("expected a duration, got %q", rhs)
+		}
+		rhsVal = time.Duration(ms * float64(time.Millisecond))
+	}
+
+	switch op {
+	case "==":
+		return lhs == rhsVal, nil
+	case "!=":
+		return lhs != rhsVal, nil
+	case "<":
+		return lhs < rhsVal, nil
+	case "<=":
+		return lhs <= rhsVal, nil
+	case ">":
+		return lhs > rhsVal, nil
+	case ">=":
+		return lhs >= rhsVal, nil
+	default:
+		return false, fmt.Errorf("unsupported operator: %s", op)
+	}
+}
+
+// compareStrings compares a string left-hand side against a condition's right-hand side, trimming
+// any surrounding quotes from the right-hand side.
+func compareStrings(lhs string, op string, rhs string) (bool, error) {
+	rhs = strings.Trim(rhs, `"`)
+
+	switch op {
+	case "==":
+		return lhs == rhs, nil
+	case "!=":
+		return lhs != rhs, nil
+	default:
+		return false, fmt.Errorf("unsupported operator for string comparison: %s", op)
+	}
+}
+
+// compareHeader resolves a header name (e.g. "Content-Type") from the response headers and
+// compares its value against a condition's right-hand side.
+func compareHeader(headers http.Header, name string, op string, rhs string) (bool, error) {
+	if name == "" {
+		return false, fmt.Errorf("malformed condition placeholder: missing header name")
+	}
+
+	return compareStrings(headers.Get(name), op, rhs)
+}
+
+// compareBody resolves a dot-separated path (e.g. "status" or "data.id") within a JSON response
+// body and compares the resolved value against a condition's right-hand side.
+func compareBody(body []byte, path string, op string, rhs string) (bool, error) {
+	var parsed any
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return false, fmt.Errorf("response body is not valid JSON: %v", err)
+	}
+
+	value := parsed
+	if path != "" {
+		for _, segment := range strings.Split(path, ".") {
+			m, ok := value.(map[string]any)
+			if !ok {
+				return false, fmt.Errorf("body path %q does not resolve to an object", path)
+			}
+
+			value, ok = m[segment]
+			if !ok {
+				return false, fmt.Errorf("body path %q not found", path)
+			}
+		}
+	}
+
+	if num, ok := value.(float64); ok {
+		return compareNumbers(num, op, rhs)
+	}
+
+	return compareStrings(fmt.Sprintf("%v", value), op, rhs)
+}
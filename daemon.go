@@ -0,0 +1,198 @@
+package whatsup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultHistorySize = 100
+	defaultListenAddr  = ":8080"
+	defaultInterval    = 30 * time.Second
+	defaultTimeout     = 10 * time.Second
+)
+
+// Run starts whatsup as a long-running daemon: every endpoint is checked on its own interval and
+// the results are cached in a Registry that backs an embedded HTTP API and a Prometheus /metrics
+// endpoint. Every check also runs through the same alert de-duplication, recovery-script, and
+// notification pipeline as the one-shot Sup, backed by its own AlertStore. Run blocks until ctx is
+// canceled, then shuts the HTTP server down gracefully.
+func Run(ctx context.Context, cfg Config) error {
+	os, err := checkOS()
+	if err != nil {
+		return err
+	}
+
+	registry := NewRegistry(defaultHistorySize)
+
+	store, err := LoadAlertStore(cfg.AlertStateFile)
+	if err != nil {
+		return fmt.Errorf("error loading alert state: %v", err)
+	}
+
+	for _, ep := range cfg.Endpoints {
+		go scheduleEndpoint(ctx, cfg, ep, os, registry, store)
+	}
+
+	addr := cfg.ListenAddr
+	if addr == "" {
+		addr = defaultListenAddr
+	}
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: newAPIHandler(cfg, registry),
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+// scheduleEndpoint probes ep on its own interval until ctx is canceled, recording every result in
+// the registry. It checks once immediately so the registry isn't empty while waiting for the
+// first tick.
+func scheduleEndpoint(ctx context.Context, cfg Config, ep Endpoint, os string, registry *Registry, store *AlertStore) {
+	interval := time.Duration(ep.Interval)
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	recordOnce(ctx, cfg, ep, os, registry, store)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			recordOnce(ctx, cfg, ep, os, registry, store)
+		}
+	}
+}
+
+// recordOnce probes ep a single time, subject to its configured timeout, records the outcome in
+// the registry, and then runs the result through the same alerting pipeline as the one-shot Sup:
+// evaluating it against store for de-duplication, running RecoverScript when it newly goes down,
+// and dispatching a notification when an event fires.
+func recordOnce(ctx context.Context, cfg Config, ep Endpoint, os string, registry *Registry, store *AlertStore) {
+	timeout := time.Duration(ep.Timeout)
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	result, probeErr := probeEndpoint(checkCtx, ep, os)
+
+	status := EndpointStatus{Timestamp: start, ResponseTime: result.ResponseTime}
+	checkResult := CheckResult{Endpoint: ep.Name}
+
+	switch {
+	case probeErr != nil:
+		status.Err = probeErr.Error()
+		checkResult.Err = probeErr
+	default:
+		ok, condErr := evaluateConditions(ep.Conditions, result)
+		switch {
+		case condErr != nil:
+			status.Err = condErr.Error()
+			checkResult.Err = condErr
+		case !ok:
+			checkResult.Err = fmt.Errorf("%s did not meet its conditions", ep.Name)
+			status.Err = checkResult.Err.Error()
+		default:
+			status.Up = true
+			checkResult.Up = true
+		}
+	}
+
+	registry.Record(ep.Name, status)
+
+	event, pending, fire := evaluateAlert(ep, checkResult, store, start)
+
+	if cfg.AllowRecoverScripts && event.Kind == "down" && ep.RecoverScript != "" {
+		result := runRecoverScript(ctx, ep)
+		event.Recover = &result
+	}
+
+	if err := store.Save(); err != nil {
+		fmt.Printf("error saving alert state: %v\n", err)
+	}
+
+	if !fire {
+		return
+	}
+
+	if errs := notifyAll(ctx, cfg.Notifiers, summarizeAlertEvents([]AlertEvent{event})); len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Printf("notifier error: %v\n", e)
+		}
+		return
+	}
+
+	// Only commit the Alerting/LastNotified transition now that notifyAll has actually succeeded,
+	// so a delivery failure above doesn't permanently suppress the retry on the next tick.
+	store.Set(ep.Name, pending)
+	if err := store.Save(); err != nil {
+		fmt.Printf("error saving alert state: %v\n", err)
+	}
+}
+
+// newAPIHandler builds the embedded HTTP API: endpoint listing, per-endpoint status history,
+// health, and Prometheus metrics.
+func newAPIHandler(cfg Config, registry *Registry) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v1/endpoints", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(cfg.Endpoints)
+	})
+
+	mux.HandleFunc("/api/v1/endpoints/", func(w http.ResponseWriter, r *http.Request) {
+		name, action, found := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/v1/endpoints/"), "/")
+		if !found || action != "statuses" {
+			http.NotFound(w, r)
+			return
+		}
+
+		n := 0
+		if raw := r.URL.Query().Get("n"); raw != "" {
+			if parsed, err := strconv.Atoi(raw); err == nil {
+				n = parsed
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(registry.Statuses(name, n))
+	})
+
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writeMetrics(w, cfg.Endpoints, registry)
+	})
+
+	return mux
+}
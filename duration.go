@@ -0,0 +1,32 @@
+package whatsup
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration is a time.Duration that marshals to and from JSON as a duration string (e.g. "30s")
+// instead of a raw count of nanoseconds.
+type Duration time.Duration
+
+// UnmarshalJSON parses a JSON duration string, e.g. "30s", into a Duration.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %v", s, err)
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON renders the Duration as a JSON duration string, e.g. "30s".
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
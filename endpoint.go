@@ -0,0 +1,233 @@
+package whatsup
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Endpoint describes a single target to monitor: where to reach it, how to check it, and what
+// conditions must hold for the check to be considered up.
+type Endpoint struct {
+	Name       string   `json:"name"`
+	Target     string   `json:"target"`
+	Type       string   `json:"type"` // "icmp", "http", "tcp", "dns", or "ssh"
+	Conditions []string `json:"conditions"`
+
+	// Interval and Timeout only apply in daemon mode (see Run); Interval defaults to 30s and
+	// Timeout to 10s when unset.
+	Interval Duration `json:"interval,omitempty"`
+	Timeout  Duration `json:"timeout,omitempty"`
+
+	// FailureThreshold and SuccessThreshold control alert de-duplication (see evaluateAlert):
+	// a down notification only fires after this many consecutive failures, and a resolved
+	// notification only fires after this many consecutive successes. They default to 3 and 2.
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+	SuccessThreshold int `json:"successThreshold,omitempty"`
+
+	// ReminderInterval, if set, re-fires a notification on this cadence while the endpoint
+	// remains down, instead of notifying only once per outage.
+	ReminderInterval Duration `json:"reminderInterval,omitempty"`
+
+	// RecoverScript, if set, is run through the shell whenever the endpoint is confirmed down
+	// (gated behind Config.AllowRecoverScripts). RecoverTimeout bounds how long it may run,
+	// defaulting to 30s.
+	RecoverScript  string   `json:"recoverScript,omitempty"`
+	RecoverTimeout Duration `json:"recoverTimeout,omitempty"`
+}
+
+// httpProbeClient is shared by every probeHTTP call so checks against the same endpoint reuse
+// pooled, keep-alive connections instead of paying a fresh TLS/TCP handshake every attempt.
+var httpProbeClient = &http.Client{
+	Transport: &http.Transport{
+		DisableKeepAlives:   false,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	},
+}
+
+// Result holds everything a probe observed about an endpoint so its conditions can be evaluated
+// against it.
+type Result struct {
+	StatusCode     int
+	Headers        http.Header
+	Body           []byte
+	IP             string
+	CertExpiration time.Time
+	ResponseTime   time.Duration
+}
+
+// probeEndpoint runs a single check against the endpoint's target using its configured type.
+func probeEndpoint(ctx context.Context, ep Endpoint, os string) (Result, error) {
+	switch ep.Type {
+	case "icmp":
+		return probeICMP(ctx, ep.Target, os)
+	case "http":
+		return probeHTTP(ctx, ep.Target)
+	case "tcp":
+		return probeTCP(ctx, ep.Target)
+	case "dns":
+		return probeDNS(ctx, ep.Target)
+	case "ssh":
+		return probeSSH(ctx, ep.Target)
+	default:
+		return Result{}, fmt.Errorf("unknown endpoint type: %s", ep.Type)
+	}
+}
+
+// probeICMP pings the target once using the native OS ping command.
+func probeICMP(ctx context.Context, target string, os string) (Result, error) {
+	countFlag := "-c"
+	if os == "windows" {
+		countFlag = "-n"
+	}
+
+	start := time.Now()
+	output, err := exec.CommandContext(ctx, "ping", target, countFlag, "1").Output()
+	responseTime := time.Since(start)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var successOutput string
+	switch os {
+	case "windows":
+		successOutput = "Packets: Sent = 1, Received = 1"
+	case "darwin":
+		successOutput = "1 packets transmitted, 1 packets received"
+	default:
+		successOutput = "1 packets transmitted, 1 received"
+	}
+
+	if !strings.Contains(string(output), successOutput) {
+		return Result{}, fmt.Errorf("%s failed to return the packet", target)
+	}
+
+	return Result{IP: resolveIP(ctx, target), ResponseTime: responseTime}, nil
+}
+
+// probeHTTP issues a GET request against the target and captures its status, headers, body,
+// response time, and TLS certificate expiration.
+func probeHTTP(ctx context.Context, target string) (Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return Result{}, err
+	}
+
+	start := time.Now()
+	resp, err := httpProbeClient.Do(req)
+	responseTime := time.Since(start)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{
+		StatusCode:   resp.StatusCode,
+		Headers:      resp.Header,
+		Body:         body,
+		ResponseTime: responseTime,
+		IP:           resolveIP(ctx, req.URL.Hostname()),
+	}
+
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		result.CertExpiration = resp.TLS.PeerCertificates[0].NotAfter
+	}
+
+	return result, nil
+}
+
+// probeTCP opens a TCP connection to the target and measures how long the handshake took.
+func probeTCP(ctx context.Context, target string) (Result, error) {
+	var d net.Dialer
+
+	start := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", target)
+	responseTime := time.Since(start)
+	if err != nil {
+		return Result{}, err
+	}
+	defer conn.Close()
+
+	host, _, err := net.SplitHostPort(target)
+	if err != nil {
+		host = target
+	}
+
+	return Result{IP: resolveIP(ctx, host), ResponseTime: responseTime}, nil
+}
+
+// probeDNS resolves the target hostname and reports how long the lookup took.
+func probeDNS(ctx context.Context, target string) (Result, error) {
+	start := time.Now()
+	ips, err := net.DefaultResolver.LookupHost(ctx, target)
+	responseTime := time.Since(start)
+	if err != nil {
+		return Result{}, err
+	}
+	if len(ips) == 0 {
+		return Result{}, fmt.Errorf("no addresses found for %s", target)
+	}
+
+	return Result{IP: ips[0], ResponseTime: responseTime}, nil
+}
+
+// probeSSH opens a TCP connection to the target and confirms it is speaking the SSH protocol by
+// reading its identification banner.
+func probeSSH(ctx context.Context, target string) (Result, error) {
+	var d net.Dialer
+
+	start := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", target)
+	if err != nil {
+		return Result{}, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetReadDeadline(deadline)
+	}
+
+	banner, err := bufio.NewReader(conn).ReadString('\n')
+	responseTime := time.Since(start)
+	if err != nil {
+		return Result{}, err
+	}
+	if !strings.HasPrefix(banner, "SSH-") {
+		return Result{}, fmt.Errorf("%s did not return an SSH identification banner", target)
+	}
+
+	host, _, err := net.SplitHostPort(target)
+	if err != nil {
+		host = target
+	}
+
+	return Result{IP: resolveIP(ctx, host), ResponseTime: responseTime}, nil
+}
+
+// resolveIP returns host's first resolved IP address, or an empty string if host is not an IP and
+// cannot be resolved.
+func resolveIP(ctx context.Context, host string) string {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String()
+	}
+
+	ips, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil || len(ips) == 0 {
+		return ""
+	}
+
+	return ips[0]
+}
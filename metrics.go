@@ -0,0 +1,40 @@
+package whatsup
+
+import (
+	"fmt"
+	"io"
+)
+
+// writeMetrics renders the registry's current state as Prometheus text exposition format.
+func writeMetrics(w io.Writer, endpoints []Endpoint, registry *Registry) {
+	fmt.Fprintln(w, "# HELP whatsup_endpoint_up Whether the endpoint's most recent check was up (1) or down (0).")
+	fmt.Fprintln(w, "# TYPE whatsup_endpoint_up gauge")
+	for _, ep := range endpoints {
+		status, ok := registry.Latest(ep.Name)
+		if !ok {
+			continue
+		}
+
+		up := 0
+		if status.Up {
+			up = 1
+		}
+		fmt.Fprintf(w, "whatsup_endpoint_up{endpoint=%q} %d\n", ep.Name, up)
+	}
+
+	fmt.Fprintln(w, "# HELP whatsup_endpoint_response_time_seconds The most recent check's response time in seconds.")
+	fmt.Fprintln(w, "# TYPE whatsup_endpoint_response_time_seconds gauge")
+	for _, ep := range endpoints {
+		status, ok := registry.Latest(ep.Name)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(w, "whatsup_endpoint_response_time_seconds{endpoint=%q} %f\n", ep.Name, status.ResponseTime.Seconds())
+	}
+
+	fmt.Fprintln(w, "# HELP whatsup_endpoint_check_total The total number of checks performed against the endpoint.")
+	fmt.Fprintln(w, "# TYPE whatsup_endpoint_check_total counter")
+	for _, ep := range endpoints {
+		fmt.Fprintf(w, "whatsup_endpoint_check_total{endpoint=%q} %d\n", ep.Name, registry.Count(ep.Name))
+	}
+}
@@ -0,0 +1,325 @@
+package whatsup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// NotifierConfig describes a single configured notification destination.
+type NotifierConfig struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`   // "teams", "discord", "slack", "webhook", or "email"
+	Events []string `json:"events"` // "success" and/or "failure"; defaults to both
+
+	// Url is the webhook URL used by the teams, discord, slack, and webhook types.
+	Url string `json:"url,omitempty"`
+
+	// Template, Headers, BasicAuthUser, and BasicAuthPass are only used by the webhook type.
+	Template      string            `json:"template,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	BasicAuthUser string            `json:"basicAuthUser,omitempty"`
+	BasicAuthPass string            `json:"basicAuthPass,omitempty"`
+
+	// SMTPHost, SMTPPort, SMTPUsername, SMTPPassword, From, and To are only used by the email type.
+	SMTPHost     string   `json:"smtpHost,omitempty"`
+	SMTPPort     int      `json:"smtpPort,omitempty"`
+	SMTPUsername string   `json:"smtpUsername,omitempty"`
+	SMTPPassword string   `json:"smtpPassword,omitempty"`
+	From         string   `json:"from,omitempty"`
+	To           []string `json:"to,omitempty"`
+}
+
+// wantsEvent reports whether the notifier config is configured to fire for the given check summary.
+func (nc NotifierConfig) wantsEvent(checkSummary CheckSummary) bool {
+	event := "failure"
+	if checkSummary.AllUp {
+		event = "success"
+	}
+
+	if len(nc.Events) == 0 {
+		return true
+	}
+
+	for _, e := range nc.Events {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Notifier sends a check summary to a single notification destination.
+type Notifier interface {
+	Notify(ctx context.Context, checkSummary CheckSummary) error
+}
+
+// NewNotifier builds the concrete Notifier described by the provided NotifierConfig.
+func NewNotifier(nc NotifierConfig) (Notifier, error) {
+	switch nc.Type {
+	case "teams":
+		return &TeamsNotifier{WebhookUrl: nc.Url}, nil
+	case "discord":
+		return &DiscordNotifier{WebhookUrl: nc.Url}, nil
+	case "slack":
+		return &SlackNotifier{WebhookUrl: nc.Url}, nil
+	case "webhook":
+		return &GenericWebhookNotifier{
+			Url:           nc.Url,
+			Template:      nc.Template,
+			Headers:       nc.Headers,
+			BasicAuthUser: nc.BasicAuthUser,
+			BasicAuthPass: nc.BasicAuthPass,
+		}, nil
+	case "email":
+		return &SMTPEmailNotifier{
+			Host:     nc.SMTPHost,
+			Port:     nc.SMTPPort,
+			Username: nc.SMTPUsername,
+			Password: nc.SMTPPassword,
+			From:     nc.From,
+			To:       nc.To,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown notifier type: %s", nc.Type)
+	}
+}
+
+// postJSON posts the given payload to url as JSON and treats any non-2xx response as an error.
+func postJSON(ctx context.Context, url string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send message with status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// summaryTitleAndColor returns the title and theme color used by the card-style notifiers for the given check summary.
+func summaryTitleAndColor(checkSummary CheckSummary) (title string, color string) {
+	if checkSummary.AllUp {
+		return "👍 Endpoints Up", "#0ac404"
+	}
+
+	return "🔥 ENDPOINTS DOWN", "#e81515"
+}
+
+// TeamsNotifier sends a check summary to a Microsoft Teams channel via an incoming webhook.
+type TeamsNotifier struct {
+	WebhookUrl string
+}
+
+// Notify sends the check summary to the configured Teams webhook.
+func (n *TeamsNotifier) Notify(ctx context.Context, checkSummary CheckSummary) error {
+	title, color := summaryTitleAndColor(checkSummary)
+
+	card := map[string]string{
+		"@type":      "MessageCard",
+		"@context":   "http://schema.org/extensions",
+		"summary":    title,
+		"themeColor": color,
+		"title":      title,
+		"text":       checkSummary.Msg,
+	}
+
+	return postJSON(ctx, n.WebhookUrl, card)
+}
+
+// DiscordNotifier sends a check summary to a Discord channel via an incoming webhook.
+type DiscordNotifier struct {
+	WebhookUrl string
+}
+
+// Notify sends the check summary to the configured Discord webhook.
+func (n *DiscordNotifier) Notify(ctx context.Context, checkSummary CheckSummary) error {
+	title, _ := summaryTitleAndColor(checkSummary)
+
+	// Discord embed colors are a decimal int, not a hex string.
+	var colorInt int
+	if checkSummary.AllUp {
+		colorInt = 0x0ac404
+	} else {
+		colorInt = 0xe81515
+	}
+
+	payload := map[string]any{
+		"embeds": []map[string]any{
+			{
+				"title":       title,
+				"description": checkSummary.Msg,
+				"color":       colorInt,
+			},
+		},
+	}
+
+	return postJSON(ctx, n.WebhookUrl, payload)
+}
+
+// SlackNotifier sends a check summary to a Slack channel via an incoming webhook.
+type SlackNotifier struct {
+	WebhookUrl string
+}
+
+// Notify sends the check summary to the configured Slack webhook.
+func (n *SlackNotifier) Notify(ctx context.Context, checkSummary CheckSummary) error {
+	title, color := summaryTitleAndColor(checkSummary)
+
+	payload := map[string]any{
+		"attachments": []map[string]any{
+			{
+				"color": color,
+				"title": title,
+				"text":  checkSummary.Msg,
+			},
+		},
+	}
+
+	return postJSON(ctx, n.WebhookUrl, payload)
+}
+
+// GenericWebhookNotifier posts a templated JSON payload to an arbitrary webhook URL, with optional
+// custom headers and HTTP basic auth.
+type GenericWebhookNotifier struct {
+	Url           string
+	Template      string
+	Headers       map[string]string
+	BasicAuthUser string
+	BasicAuthPass string
+}
+
+// defaultWebhookTemplate is used when a GenericWebhookNotifier is not configured with its own Template.
+const defaultWebhookTemplate = `{"allUp": {{.AllUp}}, "message": {{.Msg | printf "%q"}}}`
+
+// Notify renders the configured template with the check summary and posts it to the webhook URL.
+func (n *GenericWebhookNotifier) Notify(ctx context.Context, checkSummary CheckSummary) error {
+	tmplText := n.Template
+	if tmplText == "" {
+		tmplText = defaultWebhookTemplate
+	}
+
+	tmpl, err := template.New("webhook").Parse(tmplText)
+	if err != nil {
+		return fmt.Errorf("failed to parse webhook template: %v", err)
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, checkSummary); err != nil {
+		return fmt.Errorf("failed to render webhook template: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.Url, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range n.Headers {
+		req.Header.Set(k, v)
+	}
+	if n.BasicAuthUser != "" || n.BasicAuthPass != "" {
+		req.SetBasicAuth(n.BasicAuthUser, n.BasicAuthPass)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send message with status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SMTPEmailNotifier sends a check summary as an email via an SMTP server.
+type SMTPEmailNotifier struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// Notify sends the check summary as an email to the configured recipients.
+func (n *SMTPEmailNotifier) Notify(ctx context.Context, checkSummary CheckSummary) error {
+	title, _ := summaryTitleAndColor(checkSummary)
+
+	var msg strings.Builder
+	msg.WriteString(fmt.Sprintf("From: %s\r\n", n.From))
+	msg.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(n.To, ", ")))
+	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", title))
+	msg.WriteString("\r\n")
+	msg.WriteString(checkSummary.Msg)
+
+	addr := fmt.Sprintf("%s:%d", n.Host, n.Port)
+
+	var auth smtp.Auth
+	if n.Username != "" || n.Password != "" {
+		auth = smtp.PlainAuth("", n.Username, n.Password, n.Host)
+	}
+
+	return smtp.SendMail(addr, auth, n.From, n.To, []byte(msg.String()))
+}
+
+// notifyAll dispatches the check summary to every configured notifier in parallel, skipping any
+// notifier that isn't configured to fire for this summary's event type. It returns one error per
+// notifier that failed, each annotated with the notifier's name.
+func notifyAll(ctx context.Context, notifierConfigs []NotifierConfig, checkSummary CheckSummary) []error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+
+	for _, nc := range notifierConfigs {
+		if !nc.wantsEvent(checkSummary) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(nc NotifierConfig) {
+			defer wg.Done()
+
+			notifier, err := NewNotifier(nc)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("notifier %q: %v", nc.Name, err))
+				mu.Unlock()
+				return
+			}
+
+			if err := notifier.Notify(ctx, checkSummary); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("notifier %q: %v", nc.Name, err))
+				mu.Unlock()
+			}
+		}(nc)
+	}
+
+	wg.Wait()
+
+	return errs
+}
@@ -0,0 +1,71 @@
+package whatsup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRecoverTimeout  = 30 * time.Second
+	maxRecoverOutputLength = 2000
+)
+
+// RecoverResult captures the outcome of running an endpoint's RecoverScript.
+type RecoverResult struct {
+	ExitCode int
+	Output   string
+	Err      error
+}
+
+// runRecoverScript runs the endpoint's configured RecoverScript through the shell, capturing its
+// combined stdout/stderr and exit code. The script is given RecoverTimeout to finish, defaulting
+// to 30s.
+func runRecoverScript(ctx context.Context, ep Endpoint) RecoverResult {
+	timeout := time.Duration(ep.RecoverTimeout)
+	if timeout <= 0 {
+		timeout = defaultRecoverTimeout
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(runCtx, "sh", "-c", ep.RecoverScript).CombinedOutput()
+
+	result := RecoverResult{Output: truncateOutput(string(output))}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		result.ExitCode = 0
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		result.Err = err
+	}
+
+	return result
+}
+
+// formatRecoverResult renders a RecoverResult for inclusion in a notification message.
+func formatRecoverResult(r RecoverResult) string {
+	if r.Err != nil {
+		return fmt.Sprintf("Recovery script failed to run: %v \n\n", r.Err)
+	}
+
+	return fmt.Sprintf("Recovery script exited %d:\n%s\n\n", r.ExitCode, r.Output)
+}
+
+// truncateOutput trims s and caps it at maxRecoverOutputLength, marking it as truncated when cut
+// short, so a runaway recovery script can't blow up a notification payload.
+func truncateOutput(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= maxRecoverOutputLength {
+		return s
+	}
+
+	return s[:maxRecoverOutputLength] + "... (truncated)"
+}
@@ -0,0 +1,85 @@
+package whatsup
+
+import (
+	"sync"
+	"time"
+)
+
+// EndpointStatus is one timestamped check outcome for an endpoint, as stored in a Registry.
+type EndpointStatus struct {
+	Timestamp    time.Time     `json:"timestamp"`
+	Up           bool          `json:"up"`
+	Err          string        `json:"err,omitempty"`
+	ResponseTime time.Duration `json:"responseTime"`
+}
+
+// Registry caches the most recent check results for every endpoint, safe for concurrent use by
+// the scheduler goroutines that write to it and the HTTP API that reads from it.
+type Registry struct {
+	mu         sync.RWMutex
+	maxHistory int
+	statuses   map[string][]EndpointStatus
+	counts     map[string]int
+}
+
+// NewRegistry creates an empty Registry that retains up to maxHistory statuses per endpoint.
+func NewRegistry(maxHistory int) *Registry {
+	return &Registry{
+		maxHistory: maxHistory,
+		statuses:   make(map[string][]EndpointStatus),
+		counts:     make(map[string]int),
+	}
+}
+
+// Record appends a status for the named endpoint, evicting the oldest entry if the endpoint's
+// history is already at capacity.
+func (r *Registry) Record(name string, status EndpointStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	history := append(r.statuses[name], status)
+	if len(history) > r.maxHistory {
+		history = history[len(history)-r.maxHistory:]
+	}
+	r.statuses[name] = history
+	r.counts[name]++
+}
+
+// Statuses returns the most recent n statuses recorded for the named endpoint, oldest first. A
+// non-positive n returns the endpoint's full retained history.
+func (r *Registry) Statuses(name string, n int) []EndpointStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	history := r.statuses[name]
+	if n <= 0 || n > len(history) {
+		n = len(history)
+	}
+
+	result := make([]EndpointStatus, n)
+	copy(result, history[len(history)-n:])
+
+	return result
+}
+
+// Latest returns the most recently recorded status for the named endpoint, if any.
+func (r *Registry) Latest(name string) (EndpointStatus, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	history := r.statuses[name]
+	if len(history) == 0 {
+		return EndpointStatus{}, false
+	}
+
+	return history[len(history)-1], true
+}
+
+// Count returns the total number of checks ever recorded for the named endpoint, regardless of
+// how much of that history has since been evicted.
+func (r *Registry) Count(name string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.counts[name]
+}
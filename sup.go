@@ -1,27 +1,51 @@
 package whatsup
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
-	"os/exec"
 	"runtime"
 	"slices"
-	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 // Config holds configuration parameters.
 type Config struct {
-	TeamsWebhookUrlSuccess string   `json:"teamsWebhookUrlSuccess"`
-	TeamsWebhookUrlFailure string   `json:"teamsWebhookUrlFailure"`
-	Endpoints              []string `json:"endpoints"`
-	Tries                  int      `json:"tries"`
-	Https                  bool     `json:"https"`
+	Notifiers []NotifierConfig `json:"notifiers"`
+	Endpoints []Endpoint       `json:"endpoints"`
+	Tries     int              `json:"tries"`
+
+	// ListenAddr is the address the embedded HTTP API listens on in daemon mode (see Run). It
+	// defaults to ":8080" when unset.
+	ListenAddr string `json:"listenAddr,omitempty"`
+
+	// AlertStateFile is the path whatsup persists its AlertStore to between runs, so that
+	// restarting the one-shot CLI doesn't re-notify about an outage it already alerted on. An
+	// empty path disables persistence.
+	AlertStateFile string `json:"alertStateFile,omitempty"`
+
+	// AllowRecoverScripts gates whether endpoints' RecoverScript commands are ever executed. It
+	// defaults to false so that recover scripts in a shared config can't run accidentally.
+	AllowRecoverScripts bool `json:"allowRecoverScripts,omitempty"`
+
+	// MaxConcurrent caps how many endpoints checkEndpoints probes at once, defaulting to 16.
+	MaxConcurrent int `json:"maxConcurrent,omitempty"`
+
+	// Timeout bounds how long a single probe attempt may take, defaulting to 10s.
+	Timeout Duration `json:"timeout,omitempty"`
+
+	// OverallTimeout bounds how long Sup's whole batch of checks may take before it returns
+	// whatever partial results have come in, defaulting to Tries * Timeout.
+	OverallTimeout Duration `json:"overallTimeout,omitempty"`
 }
 
+const (
+	defaultMaxConcurrent = 16
+	defaultCheckTimeout  = 10 * time.Second
+)
+
 // CheckResult holds endpoint ping results.
 type CheckResult struct {
 	Endpoint string
@@ -47,111 +71,130 @@ func checkOS() (string, error) {
 	}
 }
 
-// checkEndpointPing checks if the provided endpoint is up using the native OS's ping command and writes the result to the provided channel.
-func checkEndpointPing(endpoint string, tries int, ch chan<- CheckResult, os string) {
-	var triesArg string
-	if os == "windows" {
-		triesArg = "-n"
-	} else {
-		triesArg = "-c"
-	}
-
-	output, err := exec.Command("ping", endpoint, triesArg, strconv.Itoa(int(tries))).Output()
-
-	if err != nil {
-		ch <- CheckResult{endpoint, err, false}
-		return
-	}
-
-	successOutputLinux := fmt.Sprintf("%d packets transmitted, %d received", tries, tries)
-	successOutputMac := fmt.Sprintf("%d packets transmitted, %d packets received", tries, tries)
-	successOutputWindows := fmt.Sprintf("    Packets: Sent = %d, Received = %d", tries, tries)
-
-	var successOutput string
-	if os == "windows" {
-		successOutput = successOutputWindows
-	} else if os == "darwin" {
-		successOutput = successOutputMac
-	} else {
-		successOutput = successOutputLinux
-	}
-
-	if !strings.Contains(string(output), successOutput) {
-		errMsg := fmt.Errorf("%s failed to return all packets", endpoint)
-		ch <- CheckResult{endpoint, errMsg, false}
-		return
-	}
-
-	ch <- CheckResult{endpoint, nil, true}
-}
-
-// checkEndpointHttps checks if the provided endpoint is up using a https GET request and writes the result to the provided channel.
-func checkEndpointHttps(endpoint string, tries int, ch chan<- CheckResult) {
+// checkEndpoint probes the endpoint up to tries times, giving each attempt up to timeout to
+// finish, and evaluates its conditions against each probe result, writing the outcome to the
+// provided channel. An attempt counts as up only if the probe succeeds and every condition
+// passes; the endpoint is reported up only if every attempt does.
+func checkEndpoint(ctx context.Context, ep Endpoint, tries int, timeout time.Duration, ch chan<- CheckResult, os string) {
 	successfulAttempts := 0
+	var lastErr error
 
 	for i := 0; i < tries; i++ {
-		resp, err := http.Get("https://" + endpoint)
-
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		result, err := probeEndpoint(attemptCtx, ep, os)
+		cancel()
 		if err != nil {
-			// Error making the request, the endpoint is considered down
-			// fmt.Printf("Endpoint: %v Attempt %d: Error - %v\n", endpoint, i+1, err)
+			lastErr = err
 			continue
 		}
 
-		// 403 = forbidden which means server is responding
-		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusForbidden {
-			// fmt.Printf("Endpoint: %v Attempt %d: Status Code - %d\n", endpoint, i+1, resp.StatusCode)
+		ok, err := evaluateConditions(ep.Conditions, result)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if !ok {
+			lastErr = fmt.Errorf("%s did not meet its conditions", ep.Name)
 			continue
 		}
 
-		// The endpoint is up
-		// fmt.Printf("Endpoint: %v Attempt %d: Success\n", endpoint, i+1)
 		successfulAttempts++
 	}
 
 	if successfulAttempts != tries {
-		errMsg := fmt.Errorf("%s was not up for all %d attempts", endpoint, tries)
-		ch <- CheckResult{endpoint, errMsg, false}
+		if lastErr == nil {
+			lastErr = fmt.Errorf("%s was not up for all %d attempts", ep.Name, tries)
+		}
+		ch <- CheckResult{ep.Name, lastErr, false}
 		return
 	}
 
-	ch <- CheckResult{endpoint, nil, true}
+	ch <- CheckResult{ep.Name, nil, true}
 }
 
-// checkEndpoint checks if the provided endpoint is up using either a native OS ping or https request depending on the provided value of https.
-func checkEndpoint(endpoint string, tries int, ch chan<- CheckResult, os string, https bool) {
-	if https {
-		checkEndpointHttps(endpoint, tries, ch)
-	} else {
-		checkEndpointPing(endpoint, tries, ch, os)
+// checkEndpoints asynchronously checks if the provided endpoints are up, limiting the number of
+// endpoints probed at once to maxConcurrent and giving each attempt up to timeout to finish. If
+// ctx is canceled or its deadline elapses before every endpoint has reported in, checkEndpoints
+// returns whatever partial results have been collected so far rather than blocking on the rest.
+func checkEndpoints(ctx context.Context, endpoints []Endpoint, os string, tries int, maxConcurrent int, timeout time.Duration) []CheckResult {
+	if maxConcurrent <= 0 {
+		maxConcurrent = defaultMaxConcurrent
+	}
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
 	}
-}
 
-// checkEndpoints asynchronously checks if the provided endpoints are up and returns a slice of the results.
-func checkEndpoints(endpoints []string, os string, tries int, https bool) []CheckResult {
 	var wg sync.WaitGroup
 	resultChannel := make(chan CheckResult, len(endpoints))
+	semaphore := make(chan struct{}, maxConcurrent)
 
-	for _, ept := range endpoints {
+	for _, ep := range endpoints {
 		wg.Add(1)
-		go func(ept string) {
+		go func(ep Endpoint) {
 			defer wg.Done()
-			checkEndpoint(ept, tries, resultChannel, os, https)
-		}(ept)
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			checkEndpoint(ctx, ep, tries, timeout, resultChannel, os)
+		}(ep)
 	}
 
-	wg.Wait()
-	close(resultChannel)
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
 
-	var results []CheckResult
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
 
-	for r := range resultChannel {
-		results = append(results, r)
+	var results []CheckResult
+	for drained := false; !drained; {
+		select {
+		case r := <-resultChannel:
+			results = append(results, r)
+		default:
+			drained = true
+		}
 	}
 
 	return results
 }
 
+// overallTimeout returns how long a single Sup run is allowed to take before checkEndpoints
+// returns whatever partial results it has, defaulting to Tries * Timeout so that a slow or hung
+// endpoint can't block the whole batch indefinitely.
+func overallTimeout(cfg Config) time.Duration {
+	if cfg.OverallTimeout > 0 {
+		return time.Duration(cfg.OverallTimeout)
+	}
+
+	timeout := time.Duration(cfg.Timeout)
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+
+	tries := cfg.Tries
+	if tries <= 0 {
+		tries = 1
+	}
+
+	return timeout * time.Duration(tries)
+}
+
+// indexEndpointsByName returns endpoints keyed by name for quick lookup.
+func indexEndpointsByName(endpoints []Endpoint) map[string]Endpoint {
+	byName := make(map[string]Endpoint, len(endpoints))
+	for _, ep := range endpoints {
+		byName[ep.Name] = ep
+	}
+
+	return byName
+}
+
 // filterDownEndpoints filters and returns any down endpoints in the provided results.
 func filterDownEndpoints(results []CheckResult) ([]CheckResult, error) {
 	var downEndpoints []CheckResult
@@ -171,22 +214,12 @@ func filterDownEndpoints(results []CheckResult) ([]CheckResult, error) {
 	}
 }
 
-// checkAndSummarizeEndpoints checks the provided endpoints and returns a summary of their up or down status.
-func checkAndSummarizeEndpoints(endpoints []string, os string, tries int, https bool) CheckSummary {
-	results := checkEndpoints(endpoints, os, tries, https)
-
+// summarizeResults summarizes a set of check results as a single CheckSummary.
+func summarizeResults(results []CheckResult) CheckSummary {
 	downResults, err := filterDownEndpoints(results)
 
-	var checkMethod string
-
-	if https {
-		checkMethod = "ping"
-	} else {
-		checkMethod = "HTTPS GET"
-	}
-
 	if err == nil {
-		return CheckSummary{AllUp: true, Msg: fmt.Sprintf("All %d endpoints are up, and were checked using %s.", len(results), checkMethod)}
+		return CheckSummary{AllUp: true, Msg: fmt.Sprintf("All %d endpoints are up.", len(results))}
 	}
 
 	var msg strings.Builder
@@ -200,81 +233,69 @@ func checkAndSummarizeEndpoints(endpoints []string, os string, tries int, https
 	return CheckSummary{AllUp: false, Msg: msg.String()}
 }
 
-// sendSummaryMessageToTeams sends an endpoint checks summary message to a Microsoft Teams channel via a webhook.
-func sendSummaryMessageToTeams(webhookUrlSuccess string, webhookUrlFailure string, checkSummary CheckSummary) error {
-
-	var color, title string
-	success := false
-	if checkSummary.AllUp {
-		color = "#0ac404"
-		title = "👍 Endpoints Up"
-		success = true
-	} else {
-		color = "#e81515"
-		title = "🔥 ENDPOINTS DOWN"
-	}
-
-	// create a Teams message card
-	card := map[string]string{
-		"@type":      "MessageCard",
-		"@context":   "http://schema.org/extensions",
-		"summary":    title,
-		"themeColor": color,
-		"title":      title,
-		"text":       checkSummary.Msg,
-	}
-
-	// marshal the payload to JSON
-	data, err := json.Marshal(card)
+// Sup checks whether the provided endpoints are up or down and then dispatches a notification
+// for every endpoint whose status change clears its alert thresholds.
+func Sup(cfg Config) error {
+	os, err := checkOS()
 	if err != nil {
 		return err
 	}
 
-	// create a new HTTP request
-	url := webhookUrlFailure
-	if success {
-		url = webhookUrlSuccess
-	}
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
+	ctx, cancel := context.WithTimeout(context.Background(), overallTimeout(cfg))
+	defer cancel()
 
-	// execute the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	results := checkEndpoints(ctx, cfg.Endpoints, os, cfg.Tries, cfg.MaxConcurrent, time.Duration(cfg.Timeout))
+
+	fmt.Println(summarizeResults(results).Msg)
+
+	store, err := LoadAlertStore(cfg.AlertStateFile)
 	if err != nil {
-		return err
+		return fmt.Errorf("error loading alert state: %v", err)
 	}
-	defer resp.Body.Close()
 
-	// check if the request was successful
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to send message with status code: %d", resp.StatusCode)
+	evaluations := evaluateAlerts(cfg.Endpoints, results, store, time.Now())
+
+	events := make([]AlertEvent, len(evaluations))
+	for i, e := range evaluations {
+		events[i] = e.event
 	}
 
-	return nil
-}
+	if cfg.AllowRecoverScripts {
+		endpointsByName := indexEndpointsByName(cfg.Endpoints)
+		for i, e := range events {
+			ep := endpointsByName[e.Result.Endpoint]
+			if e.Kind != "down" || ep.RecoverScript == "" {
+				continue
+			}
 
-// Sup checks whether the provided endpoints are up or down and then posts a summary message to the provided Teams webhook.
-func Sup(cfg Config) error {
-	os, err := checkOS()
-	if err != nil {
-		return err
+			result := runRecoverScript(context.Background(), ep)
+			events[i].Recover = &result
+		}
 	}
 
-	checkSummary := checkAndSummarizeEndpoints(cfg.Endpoints, os, cfg.Tries, cfg.Https)
+	if err := store.Save(); err != nil {
+		return fmt.Errorf("error saving alert state: %v", err)
+	}
 
-	fmt.Println(checkSummary.Msg)
+	if len(events) == 0 {
+		return nil
+	}
 
-	err = sendSummaryMessageToTeams(cfg.TeamsWebhookUrlSuccess, cfg.TeamsWebhookUrlFailure, checkSummary)
+	if errs := notifyAll(context.Background(), cfg.Notifiers, summarizeAlertEvents(events)); len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, e := range errs {
+			msgs[i] = e.Error()
+		}
+		return fmt.Errorf("%d notifier(s) failed: %s", len(errs), strings.Join(msgs, "; "))
+	}
 
-	if err != nil {
-		return fmt.Errorf("error sending message: %v", err)
+	// Only commit the Alerting/LastNotified transition now that notifyAll has actually succeeded,
+	// so a delivery failure above doesn't permanently suppress the retry on the next run.
+	for _, e := range evaluations {
+		store.Set(e.event.Result.Endpoint, e.pending)
 	}
 
-	return nil
+	return store.Save()
 }
 
 // ParseConfig parses the provided config data into a Config struct.
@@ -1,14 +1,21 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/mikepianka/whatsup"
 )
 
 func main() {
+	daemon := flag.Bool("daemon", false, "run whatsup as a long-running daemon instead of a one-shot check")
+	flag.Parse()
+
 	cfgData, err := os.ReadFile("config.json")
 	if err != nil {
 		log.Fatalf("Error reading config.json: %v", err)
@@ -19,6 +26,17 @@ func main() {
 		log.Fatalf("Error parsing config file: %v", err)
 	}
 
+	if *daemon {
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+
+		if err := whatsup.Run(ctx, cfg); err != nil {
+			log.Fatalf("Error running daemon: %v", err)
+		}
+
+		return
+	}
+
 	err = whatsup.Sup(cfg)
 	if err != nil {
 		log.Fatalf("Error checking endpoints: %v", err)